@@ -0,0 +1,70 @@
+// Package conventionalcommit implements just enough of the Conventional Commits grammar
+// (https://www.conventionalcommits.org) for bump to decide the size of an automatic version increment: the
+// "type(scope)!: subject" header plus a "BREAKING CHANGE:" footer, nothing else in the body is interpreted.
+package conventionalcommit
+
+import (
+	"regexp"
+	"strings"
+)
+
+// header matches the first line of a Conventional Commit message, e.g. "feat(api)!: add widget endpoint",
+// capturing type, scope, the breaking-change "!" marker and subject as groups 1-4.
+var header = regexp.MustCompile(`^([a-zA-Z]+)(?:\(([^)]+)\))?(!)?: (.+)$`)
+
+// breakingFooter matches a "BREAKING CHANGE:" (or "BREAKING-CHANGE:") footer anywhere in the commit body.
+var breakingFooter = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE: `)
+
+// Increment is the version bump a Commit calls for.
+type Increment int
+
+const (
+	IncrementNone Increment = iota
+	IncrementPatch
+	IncrementMinor
+	IncrementMajor
+)
+
+// Commit is a parsed Conventional Commit header, plus whether the full message carries a breaking change.
+type Commit struct {
+	Type     string
+	Scope    string
+	Subject  string
+	Breaking bool
+}
+
+// Parse parses a raw commit message. ok is false if its header does not match the Conventional Commits grammar,
+// in which case the commit does not qualify for automatic version bumping.
+func Parse(message string) (c Commit, ok bool) {
+	firstLine, body, _ := strings.Cut(message, "\n")
+
+	matches := header.FindStringSubmatch(firstLine)
+	if matches == nil {
+		return Commit{}, false
+	}
+
+	return Commit{
+		Type:     matches[1],
+		Scope:    matches[2],
+		Subject:  matches[4],
+		Breaking: matches[3] == "!" || breakingFooter.MatchString(body),
+	}, true
+}
+
+// Increment reports the version bump c calls for: a breaking change is always major, "feat" is minor, anything
+// else that parsed as a Conventional Commit (e.g. "fix", "chore") is patch.
+func (c Commit) Increment() Increment {
+	if c.Breaking {
+		return IncrementMajor
+	}
+	if c.Type == "feat" {
+		return IncrementMinor
+	}
+	return IncrementPatch
+}
+
+// HasBreakingFooter reports whether body (a commit message with its header line already removed) carries a
+// "BREAKING CHANGE:" footer, for callers parsing the header with their own grammar.
+func HasBreakingFooter(body string) bool {
+	return breakingFooter.MatchString(body)
+}
@@ -0,0 +1,57 @@
+package conventionalcommit
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tcs := []struct {
+		message      string
+		wantOk       bool
+		wantType     string
+		wantScope    string
+		wantBreaking bool
+	}{
+		{"feat: add widget endpoint", true, "feat", "", false},
+		{"fix(api): handle nil response", true, "fix", "api", false},
+		{"feat!: drop legacy widget endpoint", true, "feat", "", true},
+		{"feat(api)!: drop legacy widget endpoint", true, "feat", "api", true},
+		{"chore: bump dependencies", true, "chore", "", false},
+		{"fix: handle nil response\n\nBREAKING CHANGE: removes the legacy response shape", true, "fix", "", true},
+		{"update readme", false, "", "", false},
+		{"", false, "", "", false},
+	}
+	for _, c := range tcs {
+		t.Run(fmt.Sprintf("message: %q", c.message), func(t *testing.T) {
+			got, ok := Parse(c.message)
+
+			assert.Equal(t, c.wantOk, ok)
+			if ok {
+				assert.Equal(t, c.wantType, got.Type)
+				assert.Equal(t, c.wantScope, got.Scope)
+				assert.Equal(t, c.wantBreaking, got.Breaking)
+			}
+		})
+	}
+}
+
+func TestCommitIncrement(t *testing.T) {
+	tcs := []struct {
+		commit Commit
+		want   Increment
+	}{
+		{Commit{Type: "feat"}, IncrementMinor},
+		{Commit{Type: "fix"}, IncrementPatch},
+		{Commit{Type: "chore"}, IncrementPatch},
+		{Commit{Type: "feat", Breaking: true}, IncrementMajor},
+		{Commit{Type: "fix", Breaking: true}, IncrementMajor},
+	}
+	for i, c := range tcs {
+		t.Run(fmt.Sprintf("Test %d: commit: %+v, want: %v", i, c.commit, c.want), func(t *testing.T) {
+			assert.Equal(t, c.want, c.commit.Increment())
+		})
+	}
+}
@@ -11,19 +11,73 @@ import (
 )
 
 var (
-	dir   string
-	help  bool
-	minor bool
-	major bool
+	dir              string
+	help             bool
+	minor            bool
+	major            bool
+	prerelease       string
+	build            string
+	module           string
+	auto             bool
+	message          string
+	sign             bool
+	key              string
+	changelogFlag    changelogValue
+	changelogPattern string
+	dryRun           bool
 )
 
+// changelogValue implements flag.Value for a bool-like flag that also accepts an optional value, e.g. -changelog
+// or -changelog=HISTORY.md. Passing -changelog on its own writes to the default path, "CHANGELOG.md".
+type changelogValue struct {
+	set  bool
+	path string
+}
+
+func (c *changelogValue) String() string {
+	return c.path
+}
+
+func (c *changelogValue) Set(s string) error {
+	c.set = true
+	// flag calls Set("true") when -changelog is passed bare (see IsBoolFlag), which is indistinguishable from an
+	// explicit "-changelog=true". Treat both as "no path given" so the default still applies.
+	if s == "true" {
+		c.path = ""
+		return nil
+	}
+	c.path = s
+	return nil
+}
+
+// IsBoolFlag marks changelogValue as accepting an optional value, so flag allows both -changelog and
+// -changelog=path.
+func (c *changelogValue) IsBoolFlag() bool {
+	return true
+}
+
 func main() {
 	flag.BoolVar(&help, "help", false, "")
 	flag.StringVar(&dir, "dir", "", "root directory of the repository you want to bump the version for. By default uses present working directory.")
 	flag.BoolVar(&minor, "minor", false, "increments the minor release version")
 	flag.BoolVar(&major, "major", false, "increments the major release version")
+	flag.StringVar(&prerelease, "prerelease", "", "creates or increments a prerelease with the given label, e.g. -minor -prerelease=rc on v1.2.3 produces v1.3.0-rc.1, then -prerelease=rc produces v1.3.0-rc.2. Omit to finalize an existing prerelease or tag a normal release.")
+	flag.StringVar(&build, "build", "", "attaches build metadata to the created tag, e.g. -build=exp.sha.5114f85 produces v1.3.0+exp.sha.5114f85")
+	flag.StringVar(&module, "module", "", "path of the Go module to bump in a multi-module repository, e.g. -module=api reads and writes tags of the form api/v0.3.3 instead of a bare v0.3.3")
+	flag.BoolVar(&auto, "auto", false, "chooses the increment automatically from the Conventional Commits since the latest version tag, instead of -minor/-major")
+	flag.StringVar(&message, "message", "", "creates an annotated tag with this message, instead of a lightweight tag. Defaults to \"Release <tag>\" if -sign is passed without -message.")
+	flag.BoolVar(&sign, "sign", false, "GPG-signs the created tag using the key passed via -key")
+	flag.StringVar(&key, "key", "", "path to an armored GPG private key to sign the tag with, used with -sign")
+	flag.Var(&changelogFlag, "changelog", "commits a changelog entry for the new tag before tagging, e.g. -changelog or -changelog=HISTORY.md. Defaults to CHANGELOG.md.")
+	flag.StringVar(&changelogPattern, "changelog-pattern", "", "regexp with a \"type\" capture group used to categorize commits in the changelog, instead of Conventional Commits parsing. Used only with -changelog.")
+	flag.BoolVar(&dryRun, "dry-run", false, "prints the planned tag name, the commit it would point at, the commits since the previous tag, and the refspec that would be pushed, without creating or pushing anything")
 	flag.Parse()
 
+	changelogPath := changelogFlag.path
+	if changelogFlag.set && changelogPath == "" {
+		changelogPath = "CHANGELOG.md"
+	}
+
 	if help {
 		fmt.Printf("Usage: %s [OPTIONS]\n\n", os.Args[0])
 		fmt.Printf("Description: command line tool to allow the current remote git tag version (format 'v0.0.0') for a given repo to be incremented. increment can be to the minor or major release if respective flags are passed, but by default will increment the bug fix release. \n")
@@ -36,8 +90,26 @@ func main() {
 		panic(errors.Wrap(err, "error: unable to open repository"))
 	}
 
-	if err := BumpVersion(repo, major, minor); err != nil {
+	opts := BumpOptions{
+		Major:            major,
+		Minor:            minor,
+		Auto:             auto,
+		Prerelease:       prerelease,
+		Build:            build,
+		Module:           module,
+		Message:          message,
+		Sign:             sign,
+		Key:              key,
+		Changelog:        changelogFlag.set,
+		ChangelogPath:    changelogPath,
+		ChangelogPattern: changelogPattern,
+		DryRun:           dryRun,
+	}
+	if err := BumpVersion(repo, opts); err != nil {
 		log.Fatal(err, "Error: failed to bump version tag and push to remote repository")
 	}
+	if dryRun {
+		return
+	}
 	fmt.Println("tag bumped and pushed to remote successfully.")
 }
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangelogValueSet(t *testing.T) {
+	tcs := []struct {
+		input    string
+		wantPath string
+	}{
+		{"true", ""},
+		{"", ""},
+		{"HISTORY.md", "HISTORY.md"},
+	}
+	for _, c := range tcs {
+		t.Run(fmt.Sprintf("input: %q, want path: %q", c.input, c.wantPath), func(t *testing.T) {
+			var v changelogValue
+			assert.NoError(t, v.Set(c.input))
+			assert.True(t, v.set)
+			assert.Equal(t, c.wantPath, v.path)
+		})
+	}
+}
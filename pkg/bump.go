@@ -2,12 +2,18 @@ package bump
 
 import (
 	"fmt"
-	"github.com/facette/natsort"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/fergus-oakley/bump/internal/conventionalcommit"
+	"github.com/go-git/go-billy/v5/util"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
 	"github.com/pkg/errors"
 	"os"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -24,6 +30,7 @@ const (
 	ErrNoTagsFound              = Error("error: no tags found on remote")
 	ErrNoVersionTagsFound       = Error("error: no tags found have expected version format")
 	ErrCannotIncrementMajAndMin = Error("error: pass either -minor OR -major flags, not both")
+	ErrAutoConflictsWithMajMin  = Error("error: -auto cannot be combined with -minor or -major")
 )
 
 type (
@@ -33,14 +40,13 @@ type (
 )
 
 func CastToVersion(version string) (Version, error) {
-	if err := validateVersionFormat(version); err != nil {
-		return Version{}, err
+	matches := versionFormat.FindStringSubmatch(version)
+	if matches == nil {
+		return Version{}, ErrVersionFormat
 	}
-	version = strings.TrimPrefix(version, "v")
 
-	stringElements := strings.Split(version, ".")
 	var intElements []int
-	for _, s := range stringElements {
+	for _, s := range matches[1:4] {
 		i, err := strconv.Atoi(s)
 		if err != nil {
 			return Version{}, err
@@ -48,17 +54,32 @@ func CastToVersion(version string) (Version, error) {
 		intElements = append(intElements, i)
 	}
 
-	return Version{majorRelease: majorRelease(intElements[0]), minorRelease: minorRelease(intElements[1]), bugFixRelease: bugFixRelease(intElements[2])}, nil
+	return Version{
+		majorRelease:  majorRelease(intElements[0]),
+		minorRelease:  minorRelease(intElements[1]),
+		bugFixRelease: bugFixRelease(intElements[2]),
+		prerelease:    matches[4],
+		build:         matches[5],
+	}, nil
 }
 
 type Version struct {
 	majorRelease  majorRelease
 	minorRelease  minorRelease
 	bugFixRelease bugFixRelease
+	prerelease    string
+	build         string
 }
 
 func (v *Version) String() string {
-	return fmt.Sprintf("v%d.%d.%d", v.majorRelease, v.minorRelease, v.bugFixRelease)
+	s := fmt.Sprintf("v%d.%d.%d", v.majorRelease, v.minorRelease, v.bugFixRelease)
+	if v.prerelease != "" {
+		s += "-" + v.prerelease
+	}
+	if v.build != "" {
+		s += "+" + v.build
+	}
+	return s
 }
 
 func (v *Version) incrementMajor() {
@@ -76,57 +97,271 @@ func (v *Version) incrementBugFix() {
 	v.bugFixRelease++
 }
 
-func BumpVersion(repo *git.Repository, major, minor bool) error {
+// BumpOptions configures a call to BumpVersion. The zero value increments the bug fix release of a bare
+// (non-module-prefixed) version tag, creating a lightweight, unsigned tag and pushing it without a changelog.
+type BumpOptions struct {
+	// Major and Minor select the release component to increment; if both are false the bug fix release is
+	// incremented instead. Mutually exclusive with each other and with Auto.
+	Major, Minor bool
+	// Auto chooses the increment automatically from the Conventional Commits made since the latest version tag.
+	Auto bool
+	// Prerelease, if non-empty, creates or increments a "<Prerelease>.N" prerelease suffix instead of a final
+	// release; Build, if non-empty, attaches build metadata to the result.
+	Prerelease, Build string
+	// Module scopes tag lookup/creation to "<Module>/vX.Y.Z" tags published by that subdirectory's Go module;
+	// empty matches bare "vX.Y.Z" tags at the repository root.
+	Module string
+	// Message creates an annotated tag with this message instead of a lightweight tag; defaults to
+	// "Release <tag>" if empty and Sign is set.
+	Message string
+	// Sign GPG-signs the created tag using Key.
+	Sign bool
+	Key  string
+	// Changelog writes a changelog entry to ChangelogPath (defaulting to "CHANGELOG.md") before tagging, grouping
+	// commits using ChangelogPattern (a regexp with a "type" capture group) if set, otherwise Conventional Commits
+	// parsing.
+	Changelog                       bool
+	ChangelogPath, ChangelogPattern string
+	// DryRun prints the planned tag, commit and refspec without creating or pushing anything.
+	DryRun bool
+}
+
+func BumpVersion(repo *git.Repository, opts BumpOptions) error {
+	major, minor, auto := opts.Major, opts.Minor, opts.Auto
+	prerelease, build, module := opts.Prerelease, opts.Build, opts.Module
+	message, sign, key := opts.Message, opts.Sign, opts.Key
+	changelog, changelogPattern, dryRun := opts.Changelog, opts.ChangelogPattern, opts.DryRun
+
+	changelogPath := opts.ChangelogPath
+	if changelog && changelogPath == "" {
+		changelogPath = "CHANGELOG.md"
+	}
+
 	tagList, err := getRemoteGitTags(repo)
 	if err != nil {
 		return errors.Wrap(err, "Unable to get remote git tags from the repo")
 	}
-	latestVersionTag, err := getLatestVersionTag(tagList)
+	latestVersionTag, err := getLatestVersionTag(tagList, module)
 	if err != nil {
 		if err == ErrNoVersionTagsFound {
-			fmt.Println("no version tags found, creating new tag version: v0.0.0")
-			if err := tag(repo, Version{}); err != nil {
+			newTag := Version{build: build}
+			if prerelease != "" {
+				newTag.prerelease = prerelease + ".1"
+			}
+			fmt.Printf("no version tags found, creating new tag version: %s\n", moduleTagName(newTag, module))
+			var preChangelogHead plumbing.Hash
+			if changelog && !dryRun {
+				preChangelogHead, err = writeChangelog(repo, newTag, module, "", changelogPath, changelogPattern)
+				if err != nil {
+					return err
+				}
+			}
+			if err := tag(repo, newTag, module, message, sign, key, preChangelogHead, changelogPath, "", dryRun); err != nil {
 				return err
 			}
 			return nil
 		}
 		return errors.Wrap(err, "unable to get latest version tag from the tag list")
 	}
-	fmt.Println("latest version tag at remote: ", latestVersionTag.String())
 
-	incrementedVersionTag, err := incrementVersion(latestVersionTag, major, minor)
+	previousTagName := moduleTagName(latestVersionTag, module)
+
+	if auto {
+		if major || minor {
+			return ErrAutoConflictsWithMajMin
+		}
+		autoMajor, autoMinor, ok, err := autoIncrement(repo, previousTagName)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("no conventional commits found since latest version tag, nothing to bump")
+			return nil
+		}
+		major, minor = autoMajor, autoMinor
+	}
+
+	fmt.Println("latest version tag at remote: ", previousTagName)
+
+	incrementedVersionTag, err := incrementVersion(latestVersionTag, major, minor, prerelease, build)
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("version tag incremented from %s to %s\n", latestVersionTag.String(), incrementedVersionTag.String())
+	fmt.Printf("version tag incremented from %s to %s\n", previousTagName, moduleTagName(incrementedVersionTag, module))
+
+	var preChangelogHead plumbing.Hash
+	if changelog && !dryRun {
+		preChangelogHead, err = writeChangelog(repo, incrementedVersionTag, module, previousTagName, changelogPath, changelogPattern)
+		if err != nil {
+			return err
+		}
+	}
 
-	if err := tag(repo, incrementedVersionTag); err != nil {
+	if err := tag(repo, incrementedVersionTag, module, message, sign, key, preChangelogHead, changelogPath, previousTagName, dryRun); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-// incrementVersion increments bug fix release by default. If minor or major flags are passed it will instead increment either of them.
-func incrementVersion(latestVersion Version, major, minor bool) (Version, error) {
-	incrementedVersion := latestVersion
-
+// incrementVersion increments bug fix release by default. If minor or major flags are passed it will instead
+// increment either of them. If prerelease is non-empty the result carries a "<prerelease>.N" suffix instead of
+// being a final release: bumping an existing matching prerelease (e.g. "rc.1") increments N, anything else starts
+// a new release line at N=1. Passing an empty prerelease, with neither major nor minor set, against a version
+// that is currently a prerelease finalizes it (drops the suffix) rather than incrementing again. build, when
+// non-empty, is attached as build metadata on the result.
+func incrementVersion(latestVersion Version, major, minor bool, prerelease, build string) (Version, error) {
 	if major && minor {
 		return latestVersion, ErrCannotIncrementMajAndMin
 	}
-	if major {
-		incrementedVersion.incrementMajor()
+
+	incrementedVersion := latestVersion
+	incrementedVersion.build = build
+
+	if prerelease == "" {
+		if latestVersion.prerelease != "" && !major && !minor {
+			incrementedVersion.prerelease = ""
+			return incrementedVersion, nil
+		}
+		switch {
+		case major:
+			incrementedVersion.incrementMajor()
+		case minor:
+			incrementedVersion.incrementMinor()
+		default:
+			incrementedVersion.incrementBugFix()
+		}
+		incrementedVersion.prerelease = ""
 		return incrementedVersion, nil
 	}
-	if minor {
-		incrementedVersion.incrementMinor()
+
+	if label, counter, ok := splitPrereleaseCounter(latestVersion.prerelease); ok && label == prerelease && !major && !minor {
+		incrementedVersion.prerelease = fmt.Sprintf("%s.%d", label, counter+1)
 		return incrementedVersion, nil
 	}
-	incrementedVersion.incrementBugFix()
+
+	switch {
+	case major:
+		incrementedVersion.incrementMajor()
+	case minor:
+		incrementedVersion.incrementMinor()
+	default:
+		incrementedVersion.incrementBugFix()
+	}
+	incrementedVersion.prerelease = prerelease + ".1"
 	return incrementedVersion, nil
 }
 
+// splitPrereleaseCounter splits a prerelease identifier of the form "<label>.<N>" (e.g. "rc.2") into its label
+// and numeric counter. ok is false if pr does not end in a numeric dot-separated identifier.
+func splitPrereleaseCounter(pr string) (label string, counter int, ok bool) {
+	idx := strings.LastIndex(pr, ".")
+	if idx == -1 {
+		return pr, 0, false
+	}
+	counter, err := strconv.Atoi(pr[idx+1:])
+	if err != nil {
+		return pr, 0, false
+	}
+	return pr[:idx], counter, true
+}
+
+// autoIncrement inspects the commits between tagName and HEAD and decides the increment a Conventional
+// Commits-driven bump calls for: major if any commit is a breaking change, else minor if any is a "feat", else
+// patch. ok is false if none of the commits since tagName parse as Conventional Commits, in which case there is
+// nothing to bump.
+func autoIncrement(repo *git.Repository, tagName string) (major, minor, ok bool, err error) {
+	commits, err := commitsSinceTag(repo, tagName)
+	if err != nil {
+		return false, false, false, err
+	}
+
+	highest := conventionalcommit.IncrementNone
+	for _, c := range commits {
+		commit, parsed := conventionalcommit.Parse(c.Message)
+		if !parsed {
+			continue
+		}
+		ok = true
+		if inc := commit.Increment(); inc > highest {
+			highest = inc
+		}
+	}
+
+	switch highest {
+	case conventionalcommit.IncrementMajor:
+		return true, false, ok, nil
+	case conventionalcommit.IncrementMinor:
+		return false, true, ok, nil
+	default:
+		return false, false, ok, nil
+	}
+}
+
+// commitsSinceTag returns every commit reachable from HEAD down to, but not including, the commit tagName
+// points at. An empty tagName walks all the way back to the root commit.
+func commitsSinceTag(repo *git.Repository, tagName string) ([]*object.Commit, error) {
+	head, err := repo.Head()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get HEAD from the opened repo")
+	}
+
+	var tagHash plumbing.Hash
+	if tagName != "" {
+		tagHash, err = resolveRemoteTagHash(repo, tagName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	commitIter, err := repo.Log(&git.LogOptions{From: head.Hash()})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to walk commit log from HEAD")
+	}
+	defer commitIter.Close()
+
+	var commits []*object.Commit
+	err = commitIter.ForEach(func(c *object.Commit) error {
+		if tagName != "" && c.Hash == tagHash {
+			return storer.ErrStop
+		}
+		commits = append(commits, c)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to collect commits since latest version tag")
+	}
+
+	return commits, nil
+}
+
+// resolveRemoteTagHash looks up the commit hash tagName points to on the "origin" remote. An annotated tag is
+// peeled to the commit it tags when its tag object is present in the local repository.
+func resolveRemoteTagHash(repo *git.Repository, tagName string) (plumbing.Hash, error) {
+	remote, err := repo.Remote("origin")
+	if err != nil {
+		return plumbing.ZeroHash, errors.Wrap(err, "unable to access remote repo")
+	}
+	refs, err := remote.List(&git.ListOptions{})
+	if err != nil {
+		return plumbing.ZeroHash, errors.Wrap(err, "unable to list references from remote repo")
+	}
+
+	for _, r := range refs {
+		if r.Name().Short() == tagName {
+			// an annotated tag's ref points at its tag object rather than at the tagged commit
+			if tagObj, err := repo.TagObject(r.Hash()); err == nil {
+				if commit, err := tagObj.Commit(); err == nil {
+					return commit.Hash, nil
+				}
+			}
+			return r.Hash(), nil
+		}
+	}
+	return plumbing.ZeroHash, errors.Errorf("unable to find tag %q on remote repo", tagName)
+}
+
 // getRemoteGitTags retrieves all remote 'refs' from the git repo, then extracts the short name of each.
 func getRemoteGitTags(repo *git.Repository) ([]string, error) {
 	remote, err := repo.Remote("origin")
@@ -151,56 +386,455 @@ func getRemoteGitTags(repo *git.Repository) ([]string, error) {
 
 }
 
-// getLatestVersionTag retrieves the latest version tag from the remote git tags.
-func getLatestVersionTag(tagList []string) (Version, error) {
-	var versionList []string
+// getLatestVersionTag retrieves the latest version tag from the remote git tags, ordered by SemVer precedence.
+// module scopes the search to tags of the form "<module>/vX.Y.Z" published by that subdirectory's Go module; an
+// empty module matches bare "vX.Y.Z" tags at the repository root.
+func getLatestVersionTag(tagList []string, module string) (Version, error) {
+	var versions []Version
 	for _, t := range tagList {
-		if err := validateVersionFormat(t); err == nil {
-			versionList = append(versionList, t)
+		suffix, ok := moduleVersionSuffix(t, module)
+		if !ok {
+			continue
 		}
+		if err := validateVersionFormat(suffix); err != nil {
+			continue
+		}
+		v, err := CastToVersion(suffix)
+		if err != nil {
+			return Version{}, err
+		}
+		versions = append(versions, v)
 	}
 
-	if len(versionList) == 0 {
+	if len(versions) == 0 {
 		return Version{}, ErrNoVersionTagsFound
 	}
 
-	natsort.Sort(versionList)
-	latestVersionStr := versionList[len(versionList)-1]
-	latestVersion, err := CastToVersion(latestVersionStr)
-	if err != nil {
-		return Version{}, err
+	sort.Slice(versions, func(i, j int) bool {
+		return comparePrecedence(versions[i], versions[j]) < 0
+	})
+
+	return versions[len(versions)-1], nil
+}
+
+// moduleVersionSuffix extracts the "vX.Y.Z..." portion of tag for the given module, leaving the SemVer grammar in
+// validateVersionFormat/CastToVersion to operate on that suffix alone. ok is false if tag isn't published by
+// module: for module == "", tag must not itself be prefixed by a subdirectory.
+func moduleVersionSuffix(tag, module string) (suffix string, ok bool) {
+	if module == "" {
+		if strings.Contains(tag, "/") {
+			return "", false
+		}
+		return tag, true
 	}
+	prefix := module + "/"
+	if !strings.HasPrefix(tag, prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(tag, prefix), true
+}
 
-	return latestVersion, nil
+// moduleTagName re-applies module's prefix (if any) to v's tag name, e.g. "api/v0.3.3".
+func moduleTagName(v Version, module string) string {
+	if module == "" {
+		return v.String()
+	}
+	return module + "/" + v.String()
 }
 
-// validateVersionFormat ensures version adheres to the format "v0.0.0"
+// versionFormat matches the full SemVer 2.0.0 grammar (see semver.org), prefixed with "v", capturing major, minor,
+// bugfix, prerelease and build metadata as groups 1-5.
+var versionFormat = regexp.MustCompile(`^v(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+// validateVersionFormat ensures version adheres to the full SemVer 2.0.0 grammar, e.g. "v1.2.3", "v1.2.3-rc.1" or
+// "v1.2.3-rc.1+build.5".
 func validateVersionFormat(version string) error {
-	if !regexp.MustCompile("^v[0-9]+\\.[0-9]+\\.[0-9]+$").MatchString(version) {
+	if !versionFormat.MatchString(version) {
 		return ErrVersionFormat
 	}
 	return nil
 }
 
-// tag creates a new tag and pushes all local tags to the remote repository.
-func tag(repo *git.Repository, newTagVersion Version) error {
-	fmt.Println("new tag: ", newTagVersion.String())
+// comparePrecedence compares a and b per SemVer precedence rules and returns -1, 0 or 1 if a sorts before, the
+// same as, or after b. Build metadata is ignored, as required by the spec.
+func comparePrecedence(a, b Version) int {
+	if c := compareInt(int(a.majorRelease), int(b.majorRelease)); c != 0 {
+		return c
+	}
+	if c := compareInt(int(a.minorRelease), int(b.minorRelease)); c != 0 {
+		return c
+	}
+	if c := compareInt(int(a.bugFixRelease), int(b.bugFixRelease)); c != 0 {
+		return c
+	}
+	return comparePrereleasePrecedence(a.prerelease, b.prerelease)
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// comparePrereleasePrecedence compares two prerelease strings per SemVer rules: a version without a prerelease
+// has higher precedence than one with, identifiers are compared dot-segment by dot-segment (numeric identifiers
+// compare numerically and are always lower precedence than alphanumeric ones, which compare lexically), and if
+// all shared segments are equal the one with more segments has higher precedence.
+func comparePrereleasePrecedence(a, b string) int {
+	if a == "" && b == "" {
+		return 0
+	}
+	if a == "" {
+		return 1
+	}
+	if b == "" {
+		return -1
+	}
+
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		if c := compareIdentifiers(aParts[i], bParts[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInt(len(aParts), len(bParts))
+}
+
+func compareIdentifiers(a, b string) int {
+	aNum, aErr := strconv.Atoi(a)
+	bNum, bErr := strconv.Atoi(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		return compareInt(aNum, bNum)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// TagPlan describes the tag that executeTag would create and push, without performing either side effect. It is
+// returned by planTag so dry-run callers and tests can inspect the outcome of a bump without touching the repo
+// or a remote.
+type TagPlan struct {
+	Name       string
+	Hash       plumbing.Hash
+	RefSpec    config.RefSpec
+	RemoteName string
+}
 
+// planTag resolves the tag that would be created for newTagVersion (prefixed with module if set) at the repo's
+// current HEAD, and the refspec it would be pushed with. It has no side effects.
+func planTag(repo *git.Repository, newTagVersion Version, module string) (TagPlan, error) {
 	h, err := repo.Head()
 	if err != nil {
-		return errors.Wrap(err, "unable to get HEAD from the opened repo")
+		return TagPlan{}, errors.Wrap(err, "unable to get HEAD from the opened repo")
 	}
 
-	if _, err := repo.CreateTag(newTagVersion.String(), h.Hash(), nil); err != nil {
+	return TagPlan{
+		Name:       moduleTagName(newTagVersion, module),
+		Hash:       h.Hash(),
+		RefSpec:    "refs/tags/*:refs/tags/*",
+		RemoteName: "origin",
+	}, nil
+}
+
+// tag creates a new tag, prefixed with module if set, and pushes all local tags to the remote repository, unless
+// dryRun is set, in which case it only prints the plan: the tag name, the commit it would point at, the commits
+// since previousTagName, and the refspec that would be pushed. If preChangelogHead is non-zero, a changelog
+// commit touching changelogPath was made on top of it; on push failure that commit is reverted, leaving the rest
+// of the working tree (including any unrelated uncommitted or untracked changes) untouched.
+func tag(repo *git.Repository, newTagVersion Version, module, message string, sign bool, key string, preChangelogHead plumbing.Hash, changelogPath, previousTagName string, dryRun bool) error {
+	plan, err := planTag(repo, newTagVersion, module)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		return printTagPlan(repo, plan, previousTagName)
+	}
+
+	return executeTag(repo, plan, message, sign, key, preChangelogHead, changelogPath)
+}
+
+// printTagPlan prints plan's tag name, the commit it would point at, the commits since previousTagName (all
+// history if previousTagName is empty), and the refspec that would be pushed, without creating or pushing
+// anything.
+func printTagPlan(repo *git.Repository, plan TagPlan, previousTagName string) error {
+	commits, err := commitsSinceTag(repo, previousTagName)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("dry run: no tag will be created and nothing will be pushed")
+	fmt.Printf("planned tag: %s\n", plan.Name)
+	fmt.Printf("tag would point at commit: %s\n", plan.Hash)
+	fmt.Printf("commits since %q:\n", previousTagName)
+	for _, c := range commits {
+		subject, _, _ := strings.Cut(c.Message, "\n")
+		fmt.Printf("  %s %s\n", c.Hash.String()[:7], subject)
+	}
+	fmt.Printf("would push refspec %q to remote %q\n", plan.RefSpec, plan.RemoteName)
+	return nil
+}
+
+// executeTag creates the tag described by plan (if message or sign is set, an annotated tag attributed to the
+// tagger identity from the repo's git config and signed with the key at path key if sign is set; otherwise a
+// lightweight tag) and pushes it, along with all other local tags, to plan.RemoteName.
+func executeTag(repo *git.Repository, plan TagPlan, message string, sign bool, key string, preChangelogHead plumbing.Hash, changelogPath string) error {
+	fmt.Println("new tag: ", plan.Name)
+
+	opts, err := createTagOptions(plan.Name, message, sign, key)
+	if err != nil {
+		return err
+	}
+
+	if _, err := repo.CreateTag(plan.Name, plan.Hash, opts); err != nil {
 		return errors.Wrap(err, "unable to create tag from the repo head hash and newly created tag version number. tag may already exist locally.")
 	}
-	fmt.Printf("new tag has been created locally (view with 'git tag -l | tail'): %s\n", newTagVersion.String())
+	fmt.Printf("new tag has been created locally (view with 'git tag -l | tail'): %s\n", plan.Name)
 
-	if err := repo.Push(&git.PushOptions{RemoteName: "origin", RefSpecs: []config.RefSpec{"refs/tags/*:refs/tags/*"}, Progress: os.Stderr}); err != nil {
-		if rollbackErr := repo.DeleteTag(newTagVersion.String()); rollbackErr != nil {
+	if err := repo.Push(&git.PushOptions{RemoteName: plan.RemoteName, RefSpecs: []config.RefSpec{plan.RefSpec}, Progress: os.Stderr}); err != nil {
+		if rollbackErr := repo.DeleteTag(plan.Name); rollbackErr != nil {
 			return errors.Wrap(rollbackErr, "push local tag to remote failed, so attempted to rollback local tag. rollback local tag failed. May require manual cleanup.")
 		}
+		if preChangelogHead != plumbing.ZeroHash {
+			if rollbackErr := resetHead(repo, preChangelogHead, changelogPath); rollbackErr != nil {
+				return errors.Wrap(rollbackErr, "push local tag to remote failed, so rolled back the local tag, but reverting the changelog commit also failed. May require manual cleanup.")
+			}
+		}
 		return errors.Wrap(err, "unable to push new tag to remote repo")
 	}
 	return nil
 }
+
+// resetHead undoes the changelog commit made before a failed push, moving HEAD back to hash and restoring
+// changelogPath to its pre-commit state (removing it if the commit created it). Unlike a hard reset, it touches
+// only changelogPath, so unrelated uncommitted or untracked changes already present in the working tree survive.
+func resetHead(repo *git.Repository, hash plumbing.Hash, changelogPath string) error {
+	wt, err := repo.Worktree()
+	if err != nil {
+		return errors.Wrap(err, "unable to get worktree")
+	}
+
+	if err := wt.Reset(&git.ResetOptions{Commit: hash, Mode: git.SoftReset}); err != nil {
+		return errors.Wrap(err, "unable to move HEAD back past the changelog commit")
+	}
+
+	commit, err := repo.CommitObject(hash)
+	if err != nil {
+		return errors.Wrap(err, "unable to load the pre-changelog commit")
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return errors.Wrap(err, "unable to load the pre-changelog tree")
+	}
+
+	file, err := tree.File(changelogPath)
+	if err != nil {
+		if err != object.ErrFileNotFound {
+			return errors.Wrap(err, "unable to look up changelog file in pre-changelog tree")
+		}
+		if _, err := wt.Remove(changelogPath); err != nil {
+			return errors.Wrap(err, "unable to remove the changelog file the changelog commit created")
+		}
+		return nil
+	}
+
+	contents, err := file.Contents()
+	if err != nil {
+		return errors.Wrap(err, "unable to read pre-changelog changelog file contents")
+	}
+	if err := util.WriteFile(wt.Filesystem, changelogPath, []byte(contents), 0644); err != nil {
+		return errors.Wrap(err, "unable to restore pre-changelog changelog file contents")
+	}
+	if _, err := wt.Add(changelogPath); err != nil {
+		return errors.Wrap(err, "unable to stage restored changelog file")
+	}
+	return nil
+}
+
+// writeChangelog renders a changelog section for the commits between previousTagName and HEAD (all history if
+// previousTagName is empty), prepends it to path (defaulting to "CHANGELOG.md", created with a "# Changelog"
+// heading if absent; the section goes below that heading when the existing file starts with it), and commits
+// the result with a "chore(release): <tag>" message. It returns the HEAD hash from before that commit, so a
+// failed push can revert it.
+func writeChangelog(repo *git.Repository, version Version, module, previousTagName, path, pattern string) (plumbing.Hash, error) {
+	if path == "" {
+		path = "CHANGELOG.md"
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return plumbing.ZeroHash, errors.Wrap(err, "unable to get HEAD before generating changelog")
+	}
+	preChangelogHead := head.Hash()
+
+	commits, err := commitsSinceTag(repo, previousTagName)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	section := renderChangelogSection(version, module, commits, pattern)
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return plumbing.ZeroHash, errors.Wrap(err, "unable to get worktree")
+	}
+
+	existing, err := util.ReadFile(wt.Filesystem, path)
+	newFile := false
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return plumbing.ZeroHash, errors.Wrap(err, "unable to read existing changelog file")
+		}
+		newFile = true
+	}
+
+	var content []byte
+	if newFile {
+		content = append([]byte("# Changelog\n\n"), []byte(section)...)
+	} else if rest, ok := strings.CutPrefix(string(existing), "# Changelog\n\n"); ok {
+		content = []byte("# Changelog\n\n" + section + rest)
+	} else {
+		content = append([]byte(section), existing...)
+	}
+
+	if err := util.WriteFile(wt.Filesystem, path, content, 0644); err != nil {
+		return plumbing.ZeroHash, errors.Wrap(err, "unable to write changelog file")
+	}
+	if _, err := wt.Add(path); err != nil {
+		return plumbing.ZeroHash, errors.Wrap(err, "unable to stage changelog file")
+	}
+
+	commitMessage := fmt.Sprintf("chore(release): %s", moduleTagName(version, module))
+	if _, err := wt.Commit(commitMessage, &git.CommitOptions{}); err != nil {
+		return plumbing.ZeroHash, errors.Wrap(err, "unable to commit changelog file")
+	}
+
+	return preChangelogHead, nil
+}
+
+// renderChangelogSection renders a Markdown section for version grouping commits into Breaking/Features/Fixes/
+// Other, keyed by the new tag name.
+func renderChangelogSection(version Version, module string, commits []*object.Commit, pattern string) string {
+	order := []string{"Breaking", "Features", "Fixes", "Other"}
+	entriesByCategory := map[string][]string{}
+
+	for _, c := range commits {
+		commitType, breaking, ok := changelogCommitType(c.Message, pattern)
+		category := "Other"
+		if ok {
+			category = changelogCategory(commitType, breaking)
+		}
+		subject, _, _ := strings.Cut(c.Message, "\n")
+		entriesByCategory[category] = append(entriesByCategory[category], subject)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s\n\n", moduleTagName(version, module))
+	for _, category := range order {
+		entries := entriesByCategory[category]
+		if len(entries) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "### %s\n\n", category)
+		for _, entry := range entries {
+			fmt.Fprintf(&b, "- %s\n", entry)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// changelogCommitType extracts the commit type and breaking-change status used to categorize a commit for the
+// changelog: via pattern (a regexp with a "type" capture group, matched against the commit's first line) if
+// pattern is non-empty, otherwise via Conventional Commits parsing. ok is false if the commit doesn't match.
+func changelogCommitType(message, pattern string) (commitType string, breaking, ok bool) {
+	if pattern == "" {
+		c, parsed := conventionalcommit.Parse(message)
+		return c.Type, c.Breaking, parsed
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", false, false
+	}
+
+	firstLine, body, _ := strings.Cut(message, "\n")
+	matches := re.FindStringSubmatch(firstLine)
+	if matches == nil {
+		return "", false, false
+	}
+
+	idx := re.SubexpIndex("type")
+	if idx < 0 || idx >= len(matches) {
+		return "", false, false
+	}
+
+	return matches[idx], strings.Contains(matches[0], "!") || conventionalcommit.HasBreakingFooter(body), true
+}
+
+// changelogCategory buckets a commit type into one of the four changelog sections.
+func changelogCategory(commitType string, breaking bool) string {
+	switch {
+	case breaking:
+		return "Breaking"
+	case commitType == "feat":
+		return "Features"
+	case commitType == "fix":
+		return "Fixes"
+	default:
+		return "Other"
+	}
+}
+
+// createTagOptions returns nil (a lightweight tag) unless message or sign is set, in which case it returns the
+// options for an annotated tag, defaulting message to "Release <tagName>" if empty, and signing with the armored
+// GPG private key at path key if sign is set.
+func createTagOptions(tagName, message string, sign bool, key string) (*git.CreateTagOptions, error) {
+	if message == "" && !sign {
+		return nil, nil
+	}
+
+	if message == "" {
+		message = fmt.Sprintf("Release %s", tagName)
+	}
+	opts := &git.CreateTagOptions{Message: message}
+
+	if sign {
+		signKey, err := loadSigningKey(key)
+		if err != nil {
+			return nil, err
+		}
+		opts.SignKey = signKey
+	}
+
+	return opts, nil
+}
+
+// loadSigningKey reads the first private key out of the armored GPG key file at path.
+func loadSigningKey(path string) (*openpgp.Entity, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to open GPG signing key")
+	}
+	defer f.Close()
+
+	entityList, err := openpgp.ReadArmoredKeyRing(f)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read armored GPG signing key")
+	}
+	if len(entityList) == 0 {
+		return nil, errors.Errorf("no keys found in signing key file %q", path)
+	}
+
+	return entityList[0], nil
+}
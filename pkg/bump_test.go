@@ -2,7 +2,16 @@ package bump
 
 import (
 	"fmt"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/stretchr/testify/assert"
+	"os"
+	"strings"
 	"testing"
 )
 
@@ -62,6 +71,36 @@ func TestCastToVersion(t *testing.T) {
 			Version{majorRelease: 0, minorRelease: 0, bugFixRelease: 0},
 			ErrVersionFormat,
 		},
+		{
+			"v1.2.3-alpha.1",
+			Version{majorRelease: 1, minorRelease: 2, bugFixRelease: 3, prerelease: "alpha.1"},
+			nil,
+		},
+		{
+			"v1.2.3-rc.2+build.5",
+			Version{majorRelease: 1, minorRelease: 2, bugFixRelease: 3, prerelease: "rc.2", build: "build.5"},
+			nil,
+		},
+		{
+			"v1.2.3+exp.sha.5114f85",
+			Version{majorRelease: 1, minorRelease: 2, bugFixRelease: 3, build: "exp.sha.5114f85"},
+			nil,
+		},
+		{
+			"v1.2.3-0.3.7",
+			Version{majorRelease: 1, minorRelease: 2, bugFixRelease: 3, prerelease: "0.3.7"},
+			nil,
+		},
+		{
+			"v1.2.3-",
+			Version{majorRelease: 0, minorRelease: 0, bugFixRelease: 0},
+			ErrVersionFormat,
+		},
+		{
+			"v1.2.3-01",
+			Version{majorRelease: 0, minorRelease: 0, bugFixRelease: 0},
+			ErrVersionFormat,
+		},
 	}
 	for _, c := range tcs {
 		t.Run(fmt.Sprintf("input: %s, want version: %+v, want error: %v", c.input, c.wantVersion, c.wantErr), func(t *testing.T) {
@@ -109,6 +148,11 @@ func TestValidateVersionFormat(t *testing.T) {
 		{"12", ErrVersionFormat},
 		{"a.b.c", ErrVersionFormat},
 		{"", ErrVersionFormat},
+		{"v1.2.3-alpha.1", nil},
+		{"v1.2.3-rc.2+build.5", nil},
+		{"v1.2.3+exp.sha.5114f85", nil},
+		{"v1.2.3-", ErrVersionFormat},
+		{"v1.2.3-01", ErrVersionFormat},
 	}
 	for _, c := range tcs {
 		t.Run(fmt.Sprintf("input: %s, want: %v", c.input, c.want), func(t *testing.T) {
@@ -120,27 +164,41 @@ func TestValidateVersionFormat(t *testing.T) {
 func TestGetLatestVersionTag(t *testing.T) {
 	tcs := []struct {
 		input       []string
+		module      string
 		wantVersion Version
 		wantErr     error
 	}{
-		{[]string{"v0.0.0"}, Version{0, 0, 0}, nil},
-		{[]string{"v143.73234.12"}, Version{143, 73234, 12}, nil},
-		{[]string{"0.0.0"}, Version{0, 0, 0}, ErrNoVersionTagsFound},
-		{[]string{"v.73234.12"}, Version{0, 0, 0}, ErrNoVersionTagsFound},
-		{[]string{"v..12"}, Version{0, 0, 0}, ErrNoVersionTagsFound},
-		{[]string{"1.73234.12"}, Version{0, 0, 0}, ErrNoVersionTagsFound},
-		{[]string{"73234.12"}, Version{0, 0, 0}, ErrNoVersionTagsFound},
-		{[]string{"12"}, Version{0, 0, 0}, ErrNoVersionTagsFound},
-		{[]string{"a.b.c"}, Version{0, 0, 0}, ErrNoVersionTagsFound},
-		{[]string{""}, Version{0, 0, 0}, ErrNoVersionTagsFound},
-
-		{[]string{"v0.0.0", "v0.0.1", "v0.1.0", "v1.0.0"}, Version{1, 0, 0}, nil},
-		{[]string{"v0.0.25", "v0.0.5"}, Version{0, 0, 25}, nil},
-		{[]string{"v0.0.25", "v1.0.5"}, Version{1, 0, 5}, nil},
+		{[]string{"v0.0.0"}, "", Version{majorRelease: 0, minorRelease: 0, bugFixRelease: 0}, nil},
+		{[]string{"v143.73234.12"}, "", Version{majorRelease: 143, minorRelease: 73234, bugFixRelease: 12}, nil},
+		{[]string{"0.0.0"}, "", Version{}, ErrNoVersionTagsFound},
+		{[]string{"v.73234.12"}, "", Version{}, ErrNoVersionTagsFound},
+		{[]string{"v..12"}, "", Version{}, ErrNoVersionTagsFound},
+		{[]string{"1.73234.12"}, "", Version{}, ErrNoVersionTagsFound},
+		{[]string{"73234.12"}, "", Version{}, ErrNoVersionTagsFound},
+		{[]string{"12"}, "", Version{}, ErrNoVersionTagsFound},
+		{[]string{"a.b.c"}, "", Version{}, ErrNoVersionTagsFound},
+		{[]string{""}, "", Version{}, ErrNoVersionTagsFound},
+
+		{[]string{"v0.0.0", "v0.0.1", "v0.1.0", "v1.0.0"}, "", Version{1, 0, 0, "", ""}, nil},
+		{[]string{"v0.0.25", "v0.0.5"}, "", Version{0, 0, 25, "", ""}, nil},
+		{[]string{"v0.0.25", "v1.0.5"}, "", Version{1, 0, 5, "", ""}, nil},
+
+		{[]string{"v1.0.0", "v1.0.0-rc.1"}, "", Version{1, 0, 0, "", ""}, nil},
+		{[]string{"v1.0.0-alpha", "v1.0.0-alpha.1"}, "", Version{1, 0, 0, "alpha.1", ""}, nil},
+		{[]string{"v1.0.0-alpha.1", "v1.0.0-alpha.beta"}, "", Version{1, 0, 0, "alpha.beta", ""}, nil},
+		{[]string{"v1.0.0-alpha.beta", "v1.0.0-beta"}, "", Version{1, 0, 0, "beta", ""}, nil},
+		{[]string{"v1.0.0-beta", "v1.0.0-beta.2"}, "", Version{1, 0, 0, "beta.2", ""}, nil},
+		{[]string{"v1.0.0-beta.2", "v1.0.0-beta.11"}, "", Version{1, 0, 0, "beta.11", ""}, nil},
+		{[]string{"v1.0.0-beta.11", "v1.0.0-rc.1"}, "", Version{1, 0, 0, "rc.1", ""}, nil},
+
+		{[]string{"v1.0.0", "api/v0.3.3", "api/v0.4.0"}, "api", Version{0, 4, 0, "", ""}, nil},
+		{[]string{"cmd/config/v0.1.11", "cmd/config/v0.2.0"}, "cmd/config", Version{0, 2, 0, "", ""}, nil},
+		{[]string{"api/v0.3.3"}, "", Version{}, ErrNoVersionTagsFound},
+		{[]string{"v1.0.0"}, "api", Version{}, ErrNoVersionTagsFound},
 	}
 	for i, c := range tcs {
-		t.Run(fmt.Sprintf("Test %d, input: %v, wantVersion: %v, wantErr: %v", i, c.input, c.wantVersion, c.wantErr), func(t *testing.T) {
-			gotVersion, gotErr := getLatestVersionTag(c.input)
+		t.Run(fmt.Sprintf("Test %d, input: %v, module: %s, wantVersion: %v, wantErr: %v", i, c.input, c.module, c.wantVersion, c.wantErr), func(t *testing.T) {
+			gotVersion, gotErr := getLatestVersionTag(c.input, c.module)
 
 			assert.Equal(t, c.wantVersion, gotVersion)
 			assert.ErrorIs(t, gotErr, c.wantErr)
@@ -149,14 +207,18 @@ func TestGetLatestVersionTag(t *testing.T) {
 }
 func TestIncrementVersion(t *testing.T) {
 	tcs := []struct {
-		major       bool
-		minor       bool
-		wantVersion Version
-		wantErr     error
+		startVersion Version
+		major        bool
+		minor        bool
+		prerelease   string
+		build        string
+		wantVersion  Version
+		wantErr      error
 	}{
 		{
-			major: false,
-			minor: false,
+			startVersion: Version{majorRelease: 5, minorRelease: 5, bugFixRelease: 5},
+			major:        false,
+			minor:        false,
 			wantVersion: Version{
 				majorRelease:  5,
 				minorRelease:  5,
@@ -165,8 +227,9 @@ func TestIncrementVersion(t *testing.T) {
 			wantErr: nil,
 		},
 		{
-			major: true,
-			minor: false,
+			startVersion: Version{majorRelease: 5, minorRelease: 5, bugFixRelease: 5},
+			major:        true,
+			minor:        false,
 			wantVersion: Version{
 				majorRelease:  6,
 				minorRelease:  0,
@@ -175,8 +238,9 @@ func TestIncrementVersion(t *testing.T) {
 			wantErr: nil,
 		},
 		{
-			major: false,
-			minor: true,
+			startVersion: Version{majorRelease: 5, minorRelease: 5, bugFixRelease: 5},
+			major:        false,
+			minor:        true,
 			wantVersion: Version{
 				majorRelease:  5,
 				minorRelease:  6,
@@ -185,8 +249,9 @@ func TestIncrementVersion(t *testing.T) {
 			wantErr: nil,
 		},
 		{
-			major: true,
-			minor: true,
+			startVersion: Version{majorRelease: 5, minorRelease: 5, bugFixRelease: 5},
+			major:        true,
+			minor:        true,
 			wantVersion: Version{
 				majorRelease:  5,
 				minorRelease:  5,
@@ -194,18 +259,374 @@ func TestIncrementVersion(t *testing.T) {
 			},
 			wantErr: ErrCannotIncrementMajAndMin,
 		},
-	}
-	for i, c := range tcs {
-		t.Run(fmt.Sprintf("Test %d: major: %t, minor: %t, wantVersion: %v, wantErr: %v", i, c.major, c.minor, c.wantVersion, c.wantErr), func(t *testing.T) {
-			v := Version{
+		{
+			startVersion: Version{majorRelease: 5, minorRelease: 5, bugFixRelease: 5},
+			prerelease:   "rc",
+			wantVersion: Version{
 				majorRelease:  5,
 				minorRelease:  5,
-				bugFixRelease: 5,
-			}
-			newVersion, err := incrementVersion(v, c.major, c.minor)
+				bugFixRelease: 6,
+				prerelease:    "rc.1",
+			},
+			wantErr: nil,
+		},
+		{
+			startVersion: Version{majorRelease: 5, minorRelease: 5, bugFixRelease: 6, prerelease: "rc.1"},
+			prerelease:   "rc",
+			wantVersion: Version{
+				majorRelease:  5,
+				minorRelease:  5,
+				bugFixRelease: 6,
+				prerelease:    "rc.2",
+			},
+			wantErr: nil,
+		},
+		{
+			startVersion: Version{majorRelease: 5, minorRelease: 5, bugFixRelease: 6, prerelease: "rc.2"},
+			wantVersion: Version{
+				majorRelease:  5,
+				minorRelease:  5,
+				bugFixRelease: 6,
+			},
+			wantErr: nil,
+		},
+		{
+			startVersion: Version{majorRelease: 5, minorRelease: 5, bugFixRelease: 5},
+			build:        "ci.123",
+			wantVersion: Version{
+				majorRelease:  5,
+				minorRelease:  5,
+				bugFixRelease: 6,
+				build:         "ci.123",
+			},
+			wantErr: nil,
+		},
+	}
+	for i, c := range tcs {
+		t.Run(fmt.Sprintf("Test %d: start: %v, major: %t, minor: %t, prerelease: %s, wantVersion: %v, wantErr: %v", i, c.startVersion.String(), c.major, c.minor, c.prerelease, c.wantVersion, c.wantErr), func(t *testing.T) {
+			newVersion, err := incrementVersion(c.startVersion, c.major, c.minor, c.prerelease, c.build)
 
 			assert.Equal(t, c.wantVersion, newVersion)
 			assert.ErrorIs(t, err, c.wantErr)
 		})
 	}
 }
+
+func TestComparePrecedence(t *testing.T) {
+	tcs := []struct {
+		a, b Version
+		want int
+	}{
+		{Version{majorRelease: 1}, Version{majorRelease: 2}, -1},
+		{Version{majorRelease: 2}, Version{majorRelease: 1}, 1},
+		{Version{majorRelease: 1}, Version{majorRelease: 1}, 0},
+		{Version{majorRelease: 1, prerelease: "alpha"}, Version{majorRelease: 1}, -1},
+		{Version{majorRelease: 1}, Version{majorRelease: 1, prerelease: "alpha"}, 1},
+		{Version{majorRelease: 1, prerelease: "alpha"}, Version{majorRelease: 1, prerelease: "alpha.1"}, -1},
+		{Version{majorRelease: 1, prerelease: "alpha.1"}, Version{majorRelease: 1, prerelease: "alpha.beta"}, -1},
+		{Version{majorRelease: 1, prerelease: "beta.2"}, Version{majorRelease: 1, prerelease: "beta.11"}, -1},
+		{Version{majorRelease: 1, prerelease: "rc.1", build: "a"}, Version{majorRelease: 1, prerelease: "rc.1", build: "z"}, 0},
+	}
+	for i, c := range tcs {
+		t.Run(fmt.Sprintf("Test %d: a: %s, b: %s, want: %d", i, c.a.String(), c.b.String(), c.want), func(t *testing.T) {
+			assert.Equal(t, c.want, comparePrecedence(c.a, c.b))
+		})
+	}
+}
+
+func TestCreateTagOptions(t *testing.T) {
+	t.Run("lightweight tag when neither message nor sign is set", func(t *testing.T) {
+		opts, err := createTagOptions("v1.0.0", "", false, "")
+
+		assert.NoError(t, err)
+		assert.Nil(t, opts)
+	})
+	t.Run("annotated tag with the given message", func(t *testing.T) {
+		opts, err := createTagOptions("v1.0.0", "my release notes", false, "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "my release notes", opts.Message)
+		assert.Nil(t, opts.SignKey)
+	})
+	t.Run("defaults the message when signing without -message", func(t *testing.T) {
+		opts, err := createTagOptions("v1.0.0", "", true, "testdata/signing-key.asc")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Release v1.0.0", opts.Message)
+		assert.NotNil(t, opts.SignKey)
+	})
+	t.Run("error on missing signing key file", func(t *testing.T) {
+		_, err := createTagOptions("v1.0.0", "", true, "testdata/does-not-exist.asc")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestChangelogCommitType(t *testing.T) {
+	tcs := []struct {
+		message      string
+		pattern      string
+		wantType     string
+		wantBreaking bool
+		wantOk       bool
+	}{
+		{"feat: add widget endpoint", "", "feat", false, true},
+		{"feat!: drop legacy widget endpoint", "", "feat", true, true},
+		{"update readme", "", "", false, false},
+		{"JIRA-123 feature: add widget endpoint", `^JIRA-\d+ (?P<type>\w+): `, "feature", false, true},
+		{"JIRA-123 feature!: drop widget endpoint", `^JIRA-\d+ (?P<type>\w+)(!)?: `, "feature", true, true},
+		{"JIRA-123 feature: add widget endpoint\n\nBREAKING CHANGE: removes old endpoint", `^JIRA-\d+ (?P<type>\w+): `, "feature", true, true},
+		{"not a match", `^JIRA-\d+ (?P<type>\w+): `, "", false, false},
+	}
+	for i, c := range tcs {
+		t.Run(fmt.Sprintf("Test %d: message: %q, pattern: %q", i, c.message, c.pattern), func(t *testing.T) {
+			commitType, breaking, ok := changelogCommitType(c.message, c.pattern)
+
+			assert.Equal(t, c.wantOk, ok)
+			assert.Equal(t, c.wantType, commitType)
+			assert.Equal(t, c.wantBreaking, breaking)
+		})
+	}
+}
+
+func TestChangelogCategory(t *testing.T) {
+	tcs := []struct {
+		commitType string
+		breaking   bool
+		want       string
+	}{
+		{"feat", false, "Features"},
+		{"fix", false, "Fixes"},
+		{"chore", false, "Other"},
+		{"feat", true, "Breaking"},
+		{"fix", true, "Breaking"},
+	}
+	for i, c := range tcs {
+		t.Run(fmt.Sprintf("Test %d: commitType: %s, breaking: %t", i, c.commitType, c.breaking), func(t *testing.T) {
+			assert.Equal(t, c.want, changelogCategory(c.commitType, c.breaking))
+		})
+	}
+}
+
+func TestRenderChangelogSection(t *testing.T) {
+	commits := []*object.Commit{
+		{Message: "feat: add widget endpoint"},
+		{Message: "fix: handle nil response"},
+		{Message: "feat!: drop legacy widget endpoint"},
+		{Message: "update readme"},
+	}
+
+	section := renderChangelogSection(Version{majorRelease: 1}, "", commits, "")
+
+	assert.Contains(t, section, "## v1.0.0")
+	assert.Contains(t, section, "### Breaking")
+	assert.Contains(t, section, "- feat!: drop legacy widget endpoint")
+	assert.Contains(t, section, "### Features")
+	assert.Contains(t, section, "- feat: add widget endpoint")
+	assert.Contains(t, section, "### Fixes")
+	assert.Contains(t, section, "- fix: handle nil response")
+	assert.Contains(t, section, "### Other")
+	assert.Contains(t, section, "- update readme")
+}
+
+func TestPlanTag(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	assert.NoError(t, err)
+
+	wt, err := repo.Worktree()
+	assert.NoError(t, err)
+
+	head, err := wt.Commit("initial commit", &git.CommitOptions{AllowEmptyCommits: true, Author: &object.Signature{Name: "test", Email: "test@example.com"}})
+	assert.NoError(t, err)
+
+	tcs := []struct {
+		version Version
+		module  string
+		want    TagPlan
+	}{
+		{Version{majorRelease: 1}, "", TagPlan{Name: "v1.0.0", Hash: head, RefSpec: "refs/tags/*:refs/tags/*", RemoteName: "origin"}},
+		{Version{majorRelease: 0, minorRelease: 4}, "api", TagPlan{Name: "api/v0.4.0", Hash: head, RefSpec: "refs/tags/*:refs/tags/*", RemoteName: "origin"}},
+	}
+	for i, c := range tcs {
+		t.Run(fmt.Sprintf("Test %d: version: %s, module: %q", i, c.version.String(), c.module), func(t *testing.T) {
+			plan, err := planTag(repo, c.version, c.module)
+
+			assert.NoError(t, err)
+			assert.Equal(t, c.want, plan)
+		})
+	}
+}
+
+func TestWriteChangelog(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	assert.NoError(t, err)
+	setTestGitConfig(t, repo)
+
+	wt, err := repo.Worktree()
+	assert.NoError(t, err)
+
+	_, err = wt.Commit("feat: add widget endpoint", &git.CommitOptions{AllowEmptyCommits: true, Author: &object.Signature{Name: "test", Email: "test@example.com"}})
+	assert.NoError(t, err)
+
+	_, err = writeChangelog(repo, Version{majorRelease: 1}, "", "", "CHANGELOG.md", "")
+	assert.NoError(t, err)
+
+	content, err := util.ReadFile(wt.Filesystem, "CHANGELOG.md")
+	assert.NoError(t, err)
+
+	headerIdx := strings.Index(string(content), "# Changelog")
+	sectionIdx := strings.Index(string(content), "## v1.0.0")
+	assert.GreaterOrEqual(t, headerIdx, 0)
+	assert.GreaterOrEqual(t, sectionIdx, 0)
+	assert.Less(t, headerIdx, sectionIdx, "top-level \"# Changelog\" heading must come before the new version section, not after it")
+}
+
+func TestResetHead(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	assert.NoError(t, err)
+	setTestGitConfig(t, repo)
+
+	wt, err := repo.Worktree()
+	assert.NoError(t, err)
+
+	preChangelogHead, err := wt.Commit("feat: add widget endpoint", &git.CommitOptions{AllowEmptyCommits: true, Author: &object.Signature{Name: "test", Email: "test@example.com"}})
+	assert.NoError(t, err)
+
+	_, err = writeChangelog(repo, Version{majorRelease: 1}, "", "", "CHANGELOG.md", "")
+	assert.NoError(t, err)
+
+	assert.NoError(t, util.WriteFile(wt.Filesystem, "wip.txt", []byte("work in progress, not yet committed"), 0644))
+
+	assert.NoError(t, resetHead(repo, preChangelogHead, "CHANGELOG.md"))
+
+	head, err := repo.Head()
+	assert.NoError(t, err)
+	assert.Equal(t, preChangelogHead, head.Hash())
+
+	_, err = wt.Filesystem.Stat("CHANGELOG.md")
+	assert.True(t, os.IsNotExist(err), "the changelog commit's file should be reverted since it did not exist before the commit")
+
+	wipContent, err := util.ReadFile(wt.Filesystem, "wip.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "work in progress, not yet committed", string(wipContent), "unrelated untracked changes must survive the rollback")
+}
+
+// setTestGitConfig sets a user.name/user.email on repo so commits made without an explicit Author (as writeChangelog
+// does) succeed.
+func setTestGitConfig(t *testing.T, repo *git.Repository) {
+	t.Helper()
+	cfg, err := repo.Config()
+	assert.NoError(t, err)
+	cfg.User.Name = "test"
+	cfg.User.Email = "test@example.com"
+	assert.NoError(t, repo.SetConfig(cfg))
+}
+
+// newRepoWithOriginRemote creates a repo on disk with a bare "origin" remote, so tests can exercise the parts of
+// this package (getRemoteGitTags, resolveRemoteTagHash) that list refs from a real remote over go-git's local
+// "file://" transport.
+func newRepoWithOriginRemote(t *testing.T) (repo *git.Repository, wt *git.Worktree) {
+	t.Helper()
+
+	remoteDir := t.TempDir()
+	_, err := git.PlainInit(remoteDir, true)
+	assert.NoError(t, err)
+
+	repo, err = git.PlainInit(t.TempDir(), false)
+	assert.NoError(t, err)
+	setTestGitConfig(t, repo)
+
+	_, err = repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{"file://" + remoteDir}})
+	assert.NoError(t, err)
+
+	wt, err = repo.Worktree()
+	assert.NoError(t, err)
+
+	return repo, wt
+}
+
+// commitAndPush commits message on wt and pushes the current branch (and any tags) to origin, returning the new
+// commit hash.
+func commitAndPush(t *testing.T, repo *git.Repository, wt *git.Worktree, message string) plumbing.Hash {
+	t.Helper()
+
+	hash, err := wt.Commit(message, &git.CommitOptions{AllowEmptyCommits: true})
+	assert.NoError(t, err)
+	assert.NoError(t, repo.Push(&git.PushOptions{RemoteName: "origin", RefSpecs: []config.RefSpec{"refs/heads/master:refs/heads/master", "refs/tags/*:refs/tags/*"}}))
+	return hash
+}
+
+func TestCommitsSinceTag(t *testing.T) {
+	t.Run("empty tagName walks the full history without touching the remote", func(t *testing.T) {
+		repo, err := git.Init(memory.NewStorage(), memfs.New())
+		assert.NoError(t, err)
+		setTestGitConfig(t, repo)
+		wt, err := repo.Worktree()
+		assert.NoError(t, err)
+
+		_, err = wt.Commit("first", &git.CommitOptions{AllowEmptyCommits: true})
+		assert.NoError(t, err)
+		_, err = wt.Commit("second", &git.CommitOptions{AllowEmptyCommits: true})
+		assert.NoError(t, err)
+
+		commits, err := commitsSinceTag(repo, "")
+		assert.NoError(t, err)
+		assert.Len(t, commits, 2)
+		assert.Equal(t, "second", commits[0].Message)
+		assert.Equal(t, "first", commits[1].Message)
+	})
+
+	t.Run("stops at the commit the remote tag points at", func(t *testing.T) {
+		repo, wt := newRepoWithOriginRemote(t)
+
+		taggedHash := commitAndPush(t, repo, wt, "feat: add widget endpoint")
+		_, err := repo.CreateTag("v1.0.0", taggedHash, nil)
+		assert.NoError(t, err)
+		assert.NoError(t, repo.Push(&git.PushOptions{RemoteName: "origin", RefSpecs: []config.RefSpec{"refs/tags/*:refs/tags/*"}}))
+
+		commitAndPush(t, repo, wt, "fix: handle nil response")
+		commitAndPush(t, repo, wt, "chore: bump dependencies")
+
+		commits, err := commitsSinceTag(repo, "v1.0.0")
+		assert.NoError(t, err)
+		assert.Len(t, commits, 2)
+		assert.Equal(t, "chore: bump dependencies", commits[0].Message)
+		assert.Equal(t, "fix: handle nil response", commits[1].Message)
+	})
+}
+
+func TestAutoIncrement(t *testing.T) {
+	tcs := []struct {
+		name      string
+		messages  []string
+		wantMajor bool
+		wantMinor bool
+		wantOk    bool
+	}{
+		{"no conventional commits", []string{"update readme"}, false, false, false},
+		{"fix only bumps patch", []string{"fix: handle nil response"}, false, false, true},
+		{"feat bumps minor", []string{"fix: handle nil response", "feat: add widget endpoint"}, false, true, true},
+		{"breaking change bumps major over feat", []string{"feat: add widget endpoint", "feat!: drop legacy widget endpoint"}, true, false, true},
+		{"BREAKING CHANGE footer bumps major", []string{"fix: handle nil response\n\nBREAKING CHANGE: removes the legacy response shape"}, true, false, true},
+	}
+	for _, c := range tcs {
+		t.Run(c.name, func(t *testing.T) {
+			repo, wt := newRepoWithOriginRemote(t)
+
+			taggedHash := commitAndPush(t, repo, wt, "initial commit")
+			_, err := repo.CreateTag("v1.0.0", taggedHash, nil)
+			assert.NoError(t, err)
+			assert.NoError(t, repo.Push(&git.PushOptions{RemoteName: "origin", RefSpecs: []config.RefSpec{"refs/tags/*:refs/tags/*"}}))
+
+			for _, m := range c.messages {
+				commitAndPush(t, repo, wt, m)
+			}
+
+			gotMajor, gotMinor, gotOk, err := autoIncrement(repo, "v1.0.0")
+			assert.NoError(t, err)
+			assert.Equal(t, c.wantMajor, gotMajor)
+			assert.Equal(t, c.wantMinor, gotMinor)
+			assert.Equal(t, c.wantOk, gotOk)
+		})
+	}
+}